@@ -0,0 +1,233 @@
+package consts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/types"
+)
+
+// Array represents an array constant.
+//
+// Examples:
+//    [2 x i32] [i32 1, i32 2]
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#complex-constants
+type Array struct {
+	typ   *types.Array
+	elems []Constant
+}
+
+// NewArray returns an array constant based on the given array type and
+// element constants.
+func NewArray(typ *types.Array, elems []Constant) (*Array, error) {
+	if len(elems) != typ.Len() {
+		return nil, fmt.Errorf("incorrect number of elements in array constant; expected %d, got %d", typ.Len(), len(elems))
+	}
+	for i, elem := range elems {
+		if elem.Type().String() != typ.Elem().String() {
+			return nil, fmt.Errorf("invalid element type in array constant at index %d; expected %q, got %q", i, typ.Elem(), elem.Type())
+		}
+	}
+	v := &Array{typ: typ, elems: elems}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *Array) Type() types.Type {
+	return v.typ
+}
+
+// String returns a string representation of the array constant, e.g.
+//
+//    [2 x i32] [i32 1, i32 2]
+func (v *Array) String() string {
+	return fmt.Sprintf("%s %s", v.Type(), elemsString(v.elems))
+}
+
+// CharArray represents a character array constant (a string literal),
+// stored as a convenience rather than as a slice of individual Int
+// constants.
+//
+// Examples:
+//    c"hello\00"
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#complex-constants
+type CharArray struct {
+	typ *types.Array
+	x   []byte
+}
+
+// NewCharArray returns a character array constant based on the given array
+// type (of i8 elements) and byte slice.
+func NewCharArray(typ *types.Array, x []byte) (*CharArray, error) {
+	if typ.Len() != len(x) {
+		return nil, fmt.Errorf("incorrect number of elements in character array constant; expected %d, got %d", typ.Len(), len(x))
+	}
+	v := &CharArray{typ: typ, x: x}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *CharArray) Type() types.Type {
+	return v.typ
+}
+
+// String returns a string representation of the character array constant,
+// e.g.
+//
+//    [6 x i8] c"hello\00"
+func (v *CharArray) String() string {
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("%s c\"", v.Type()))
+	for _, b := range v.x {
+		if isPrint(b) && b != '"' && b != '\\' {
+			buf.WriteByte(b)
+		} else {
+			fmt.Fprintf(buf, "\\%02X", b)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// isPrint reports whether b is an ASCII character safe to emit unescaped in
+// a character array constant.
+func isPrint(b byte) bool {
+	return b >= 0x20 && b < 0x7F
+}
+
+// Struct represents a struct constant.
+//
+// Examples:
+//    {i32, i8} {i32 1, i8 2}
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#complex-constants
+type Struct struct {
+	typ    *types.Struct
+	fields []Constant
+}
+
+// NewStruct returns a struct constant based on the given struct type and
+// field constants.
+func NewStruct(typ *types.Struct, fields []Constant) (*Struct, error) {
+	want := typ.Fields()
+	if len(fields) != len(want) {
+		return nil, fmt.Errorf("incorrect number of fields in struct constant; expected %d, got %d", len(want), len(fields))
+	}
+	for i, field := range fields {
+		if field.Type().String() != want[i].String() {
+			return nil, fmt.Errorf("invalid field type in struct constant at index %d; expected %q, got %q", i, want[i], field.Type())
+		}
+	}
+	v := &Struct{typ: typ, fields: fields}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *Struct) Type() types.Type {
+	return v.typ
+}
+
+// String returns a string representation of the struct constant, e.g.
+//
+//    {i32, i8} {i32 1, i8 2}
+func (v *Struct) String() string {
+	return fmt.Sprintf("%s {%s}", v.Type(), elemsBody(v.fields))
+}
+
+// Vector represents a vector constant.
+//
+// Examples:
+//    <2 x i32> <i32 1, i32 2>
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#complex-constants
+type Vector struct {
+	typ   *types.Vector
+	elems []Constant
+}
+
+// NewVector returns a vector constant based on the given vector type and
+// element constants.
+func NewVector(typ *types.Vector, elems []Constant) (*Vector, error) {
+	if len(elems) != typ.Len() {
+		return nil, fmt.Errorf("incorrect number of elements in vector constant; expected %d, got %d", typ.Len(), len(elems))
+	}
+	for i, elem := range elems {
+		if elem.Type().String() != typ.Elem().String() {
+			return nil, fmt.Errorf("invalid element type in vector constant at index %d; expected %q, got %q", i, typ.Elem(), elem.Type())
+		}
+	}
+	v := &Vector{typ: typ, elems: elems}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *Vector) Type() types.Type {
+	return v.typ
+}
+
+// String returns a string representation of the vector constant, e.g.
+//
+//    <2 x i32> <i32 1, i32 2>
+func (v *Vector) String() string {
+	return fmt.Sprintf("%s <%s>", v.Type(), elemsBody(v.elems))
+}
+
+// ZeroInitializer represents the zero value of any type.
+//
+// Examples:
+//    zeroinitializer
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#complex-constants
+type ZeroInitializer struct {
+	typ types.Type
+}
+
+// NewZeroInitializer returns a zeroinitializer constant for the given type.
+func NewZeroInitializer(typ types.Type) *ZeroInitializer {
+	return &ZeroInitializer{typ: typ}
+}
+
+// Type returns the type of the value.
+func (v *ZeroInitializer) Type() types.Type {
+	return v.typ
+}
+
+// String returns a string representation of the zeroinitializer constant,
+// e.g.
+//
+//    [2 x i32] zeroinitializer
+func (v *ZeroInitializer) String() string {
+	return fmt.Sprintf("%s zeroinitializer", v.Type())
+}
+
+// elemsString returns the LLVM syntax for an aggregate's bracketed element
+// list, e.g. "[i32 1, i32 2]".
+func elemsString(elems []Constant) string {
+	return fmt.Sprintf("[%s]", elemsBody(elems))
+}
+
+// elemsBody returns the comma-separated "T v" list shared by the array,
+// struct, and vector constant syntaxes.
+func elemsBody(elems []Constant) string {
+	ss := make([]string, len(elems))
+	for i, elem := range elems {
+		ss[i] = elem.String()
+	}
+	return strings.Join(ss, ", ")
+}
+
+// isConst ensures that only constant values can be assigned to the Constant
+// interface.
+func (*Array) isConst()           {}
+func (*CharArray) isConst()       {}
+func (*Struct) isConst()          {}
+func (*Vector) isConst()          {}
+func (*ZeroInitializer) isConst() {}
@@ -0,0 +1,117 @@
+package consts
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/types"
+)
+
+func TestArrayString(t *testing.T) {
+	i32 := types.NewInt(32)
+	typ := types.NewArray(i32, 2)
+	e0, err := NewInt(i32, "1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	e1, err := NewInt(i32, "2")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v, err := NewArray(typ, []Constant{e0, e1})
+	if err != nil {
+		t.Fatalf("NewArray returned error %v", err)
+	}
+	want := "[2 x i32] [i32 1, i32 2]"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestArrayElemCountMismatch(t *testing.T) {
+	i32 := types.NewInt(32)
+	typ := types.NewArray(i32, 2)
+	e0, err := NewInt(i32, "1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	if _, err := NewArray(typ, []Constant{e0}); err == nil {
+		t.Fatalf("expected error for element count mismatch, got none")
+	}
+}
+
+func TestArrayElemTypeMismatch(t *testing.T) {
+	i32 := types.NewInt(32)
+	i8 := types.NewInt(8)
+	typ := types.NewArray(i32, 1)
+	e0, err := NewInt(i8, "1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	if _, err := NewArray(typ, []Constant{e0}); err == nil {
+		t.Fatalf("expected error for element type mismatch, got none")
+	}
+}
+
+func TestStructString(t *testing.T) {
+	i32, i8 := types.NewInt(32), types.NewInt(8)
+	typ := types.NewStruct(i32, i8)
+	f0, err := NewInt(i32, "1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	f1, err := NewInt(i8, "2")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v, err := NewStruct(typ, []Constant{f0, f1})
+	if err != nil {
+		t.Fatalf("NewStruct returned error %v", err)
+	}
+	want := "{i32, i8} {i32 1, i8 2}"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestVectorString(t *testing.T) {
+	i32 := types.NewInt(32)
+	typ := types.NewVector(i32, 2)
+	e0, err := NewInt(i32, "1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	e1, err := NewInt(i32, "2")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v, err := NewVector(typ, []Constant{e0, e1})
+	if err != nil {
+		t.Fatalf("NewVector returned error %v", err)
+	}
+	want := "<2 x i32> <i32 1, i32 2>"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestZeroInitializerString(t *testing.T) {
+	typ := types.NewArray(types.NewInt(32), 2)
+	v := NewZeroInitializer(typ)
+	want := "[2 x i32] zeroinitializer"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestCharArrayString(t *testing.T) {
+	x := []byte("hi\x00")
+	typ := types.NewArray(types.NewInt(8), len(x))
+	v, err := NewCharArray(typ, x)
+	if err != nil {
+		t.Fatalf("NewCharArray returned error %v", err)
+	}
+	want := `[3 x i8] c"hi\00"`
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
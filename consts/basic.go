@@ -2,9 +2,12 @@ package consts
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 
+	"github.com/llir/llvm/internal/extfloat"
 	"github.com/llir/llvm/types"
 )
 
@@ -20,7 +23,7 @@ import (
 //    http://llvm.org/docs/LangRef.html#simple-constants
 type Int struct {
 	typ *types.Int
-	x   int64
+	x   *big.Int
 }
 
 // NewInt returns an integer constant based on the given integer type and string
@@ -34,19 +37,14 @@ func NewInt(typ types.Type, s string) (*Int, error) {
 		return nil, fmt.Errorf("invalid type %q for integer constant", typ)
 	}
 	size := v.typ.Size()
-	if size > 64 {
-		// TODO: Add support for large integer constants (e.g. above 64-bits).
-		err := fmt.Sprintf("not yet implemented; support for %q integer constants (e.g. above 64-bits)", typ)
-		panic(err)
-	}
 
 	// Parse boolean constant.
 	if size == 1 {
 		switch s {
 		case "1", "true":
-			v.x = 1
+			v.x = big.NewInt(1)
 		case "0", "false":
-			v.x = 0
+			v.x = big.NewInt(0)
 		default:
 			return nil, fmt.Errorf("invalid integer constant %q for boolean type", s)
 		}
@@ -55,19 +53,85 @@ func NewInt(typ types.Type, s string) (*Int, error) {
 		return nil, fmt.Errorf("integer constant %q type mismatch; expected i1, got %v", s, typ)
 	}
 
-	// TODO: Implement support for the HexIntConstant representation:
+	// Parse the HexIntConstant representation:
 	//    [us]0x[0-9A-Fa-f]+
+	if len(s) > 3 && (s[0] == 's' || s[0] == 'u') && s[1:3] == "0x" {
+		x, err := parseHexInt(s, size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer constant %q for type %q; %v", s, typ, err)
+		}
+		v.x = x
+		return v, nil
+	}
 
 	// Parse integer constant.
-	var err error
-	v.x, err = strconv.ParseInt(s, 10, size)
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse integer constant %q; %v", s, err)
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse integer constant %q", s)
+	}
+	if err := validBitWidth(x, size); err != nil {
+		return nil, fmt.Errorf("invalid integer constant %q for type %q; %v", s, typ, err)
 	}
+	v.x = x
 
 	return v, nil
 }
 
+// parseHexInt parses the hex payload of a HexIntConstant ("s0x..." or
+// "u0x...") into a two's-complement value for an integer type of the given
+// bit size.
+func parseHexInt(s string, size int) (*big.Int, error) {
+	signed := s[0] == 's'
+	hex := s[3:]
+	mag, ok := new(big.Int).SetString(hex, 16)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse hex digits %q", hex)
+	}
+
+	if !signed {
+		// Unsigned form; zero-extended.
+		if mag.BitLen() > size {
+			return nil, fmt.Errorf("value requires %d bits, width is %d bits", mag.BitLen(), size)
+		}
+		return mag, nil
+	}
+
+	// Signed form; the top bit of the hex payload is the sign bit, and the
+	// value is sign-extended from the nibble count to the target width.
+	nbits := len(hex) * 4
+	x := mag
+	if mag.Bit(nbits-1) == 1 {
+		full := new(big.Int).Lsh(big.NewInt(1), uint(nbits))
+		x = new(big.Int).Sub(mag, full)
+	}
+	if err := validBitWidth(x, size); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// validBitWidth reports an error if x does not fit within the given bit
+// width, under either its signed or its unsigned interpretation (LLVM
+// decimal integer literals accept both, e.g. "i8 -1" and "i8 255" are
+// equivalent).
+func validBitWidth(x *big.Int, size int) error {
+	if x.Sign() < 0 {
+		// x fits if x+1 needs at most size-1 bits, i.e. x >= -2^(size-1)
+		// (e.g. -128 for i8); anything more negative does not fit.
+		t := new(big.Int).Add(x, big.NewInt(1))
+		if n := t.BitLen(); n > size-1 {
+			return fmt.Errorf("value requires %d bits, width is %d bits", n+1, size)
+		}
+		return nil
+	}
+	// Positive values are accepted up to the full bit width (i.e. as an
+	// unsigned value), not just size-1 bits.
+	if n := x.BitLen(); n > size {
+		return fmt.Errorf("value requires %d bits, width is %d bits", n, size)
+	}
+	return nil
+}
+
 // Type returns the type of the value.
 func (v *Int) Type() types.Type {
 	return v.typ
@@ -84,19 +148,34 @@ func (v *Int) Type() types.Type {
 func (v *Int) String() string {
 	s := ""
 	if v.typ.Size() == 1 {
-		switch v.x {
+		switch v.x.Int64() {
 		case 1:
 			s = "true"
 		default:
 			s = "false"
 		}
 	} else {
-		s = strconv.FormatInt(v.x, 10)
+		s = v.x.String()
 	}
 
 	return fmt.Sprintf("%s %s", v.Type(), s)
 }
 
+// HexString returns the HexIntConstant representation of the integer
+// constant (e.g. "s0xFF", "u0x7B"), suitable for round-tripping values whose
+// decimal form is unwieldy.
+func (v *Int) HexString() string {
+	size := v.typ.Size()
+	mag := v.x
+	prefix := "u0x"
+	if v.x.Sign() < 0 {
+		prefix = "s0x"
+		full := new(big.Int).Lsh(big.NewInt(1), uint(size))
+		mag = new(big.Int).Add(v.x, full)
+	}
+	return prefix + strings.ToUpper(mag.Text(16))
+}
+
 // Float represents a floating point constant.
 //
 // Examples:
@@ -106,7 +185,13 @@ func (v *Int) String() string {
 //    http://llvm.org/docs/LangRef.html#simple-constants
 type Float struct {
 	typ *types.Float
-	x   float64
+	// x holds the value for float and double, which round-trip exactly
+	// through float64.
+	x float64
+	// bits holds the raw IEEE-754-style bit pattern for the extended
+	// precision types (half, x86_fp80, fp128, ppc_fp128), which require
+	// more precision or range than float64 provides.
+	bits *big.Int
 }
 
 // NewFloat returns a floating point constant based on the given floating point
@@ -120,17 +205,44 @@ func NewFloat(typ types.Type, s string) (*Float, error) {
 		return nil, fmt.Errorf("invalid type %q for floating point constant", typ)
 	}
 	size := v.typ.Size()
-	switch size {
-	case 32, 64:
-		// supported size
-	default:
-		// TODO: Add support for half, fp128, x86_fp80 and ppc_fp128.
-		err := fmt.Sprintf("not yet implemented; support for %q floating point constants", v.typ)
-		panic(err)
-	}
+	kind := v.typ.String()
 
-	// TODO: Implement support for the following representation:
+	// Parse the hex-float representation:
 	//    0x[KLMH]?[0-9A-Fa-f]+
+	if strings.HasPrefix(s, "0x") {
+		return v, v.parseHexFloat(s)
+	}
+
+	switch kind {
+	case "half":
+		f, ok := new(big.Float).SetPrec(64).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse floating point constant %q", s)
+		}
+		v.bits = new(big.Int).SetUint64(uint64(extfloat.EncodeHalf(f)))
+		return v, nil
+	case "x86_fp80":
+		f, ok := new(big.Float).SetPrec(128).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse floating point constant %q", s)
+		}
+		v.bits = extfloat.EncodeX87Ext(f)
+		return v, nil
+	case "fp128":
+		f, ok := new(big.Float).SetPrec(128).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse floating point constant %q", s)
+		}
+		v.bits = extfloat.EncodeFP128(f)
+		return v, nil
+	case "ppc_fp128":
+		f, ok := new(big.Float).SetPrec(128).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse floating point constant %q", s)
+		}
+		v.bits = extfloat.EncodePPCDouble(f)
+		return v, nil
+	}
 
 	// Parse floating point constant.
 	var err error
@@ -147,6 +259,73 @@ func NewFloat(typ types.Type, s string) (*Float, error) {
 	return v, nil
 }
 
+// parseHexFloat parses the LLVM hex-float representation
+// (0x[KLMH]?[0-9A-Fa-f]+) into v, dispatching on the optional letter prefix
+// to determine the encoding: bare 0x for double (16 hex digits of raw
+// IEEE754 bits), 0xK for x86_fp80 (20 hex digits), 0xL for fp128 (32 hex
+// digits), 0xM for ppc_fp128 (32 hex digits, two packed doubles), and 0xH
+// for half (4 hex digits).
+func (v *Float) parseHexFloat(s string) error {
+	rest := s[2:]
+	var letter byte
+	if len(rest) > 0 && (rest[0] == 'K' || rest[0] == 'L' || rest[0] == 'M' || rest[0] == 'H') {
+		letter = rest[0]
+		rest = rest[1:]
+	}
+
+	bits, ok := new(big.Int).SetString(rest, 16)
+	if !ok {
+		return fmt.Errorf("unable to parse hex digits %q of floating point constant %q", rest, s)
+	}
+
+	switch letter {
+	case 'K':
+		if v.typ.String() != "x86_fp80" {
+			return fmt.Errorf("hex-float form %q invalid for type %q", s, v.typ)
+		}
+		v.bits = bits
+	case 'L':
+		if v.typ.String() != "fp128" {
+			return fmt.Errorf("hex-float form %q invalid for type %q", s, v.typ)
+		}
+		v.bits = bits
+	case 'M':
+		if v.typ.String() != "ppc_fp128" {
+			return fmt.Errorf("hex-float form %q invalid for type %q", s, v.typ)
+		}
+		v.bits = bits
+	case 'H':
+		if v.typ.String() != "half" {
+			return fmt.Errorf("hex-float form %q invalid for type %q", s, v.typ)
+		}
+		v.bits = bits
+	case 0:
+		// Bare form; raw double bits, narrowed to float if necessary. The
+		// narrowing is done on the bit pattern directly (rather than via a
+		// Go float64-to-float32 conversion) so that NaN payloads and the
+		// quiet/signaling bit survive intact.
+		rawBits := bits.Uint64()
+		switch v.typ.Size() {
+		case 64:
+			v.x = math.Float64frombits(rawBits)
+		case 32:
+			bits32, ok := narrowFloat64BitsToFloat32Bits(rawBits)
+			if !ok {
+				return fmt.Errorf("hex-float constant %q not exactly representable as %q", s, v.typ)
+			}
+			// Widen by hand too: a Go float64(float32Value) conversion is
+			// not guaranteed to preserve a signaling NaN's quiet/signaling
+			// bit, which would defeat the point of narrowing by hand above.
+			v.x = math.Float64frombits(widenFloat32BitsToFloat64Bits(bits32))
+		default:
+			return fmt.Errorf("hex-float form %q invalid for type %q", s, v.typ)
+		}
+	default:
+		return fmt.Errorf("invalid hex-float constant %q", s)
+	}
+	return nil
+}
+
 // Type returns the type of the value.
 func (v *Float) Type() types.Type {
 	return v.typ
@@ -161,16 +340,20 @@ func (v *Float) Type() types.Type {
 //    double 3.14
 //    double -2.5e10
 func (v *Float) String() string {
-	size := v.typ.Size()
-	switch size {
-	case 32, 64:
-		// supported size
-	default:
-		// TODO: Add support for half, fp128, x86_fp80 and ppc_fp128.
-		err := fmt.Sprintf("not yet implemented; support for %q floating point constants", v.typ)
-		panic(err)
+	if v.bits != nil {
+		return fmt.Sprintf("%s %s", v.Type(), v.hexString())
+	}
+
+	// NaN, Inf and negative zero have no (unambiguous) decimal
+	// representation accepted by LLVM; emit the 16-hex-digit double bit
+	// pattern instead, widening float values to double as LLVM's own
+	// disassembler does.
+	if math.IsNaN(v.x) || math.IsInf(v.x, 0) || (v.x == 0 && math.Signbit(v.x)) {
+		return fmt.Sprintf("%s 0x%016X", v.Type(), math.Float64bits(v.x))
 	}
 
+	size := v.typ.Size()
+
 	// Insert decimal point if not present.
 	//    3e4 -> 3.0e4
 	//    42  -> 42.0
@@ -191,6 +374,25 @@ func (v *Float) String() string {
 	return fmt.Sprintf("%s %s", v.Type(), s)
 }
 
+// hexString returns the LLVM hex-float representation of an extended
+// precision floating point constant, e.g. "0xH3C00" for half, "0xK..." for
+// x86_fp80, "0xL..." for fp128, and "0xM..." for ppc_fp128.
+func (v *Float) hexString() string {
+	var letter string
+	var nibbles int
+	switch v.typ.String() {
+	case "half":
+		letter, nibbles = "H", 4
+	case "x86_fp80":
+		letter, nibbles = "K", 20
+	case "fp128":
+		letter, nibbles = "L", 32
+	case "ppc_fp128":
+		letter, nibbles = "M", 32
+	}
+	return fmt.Sprintf("0x%s%0*X", letter, nibbles, v.bits)
+}
+
 // TODO: Check if global names are used for anything except functions and global
 // variables. If so, be more specific about @foo in the example below by
 // providing a comment.
@@ -204,16 +406,51 @@ func (v *Float) String() string {
 //    http://llvm.org/docs/LangRef.html#simple-constants
 type Pointer struct {
 	typ *types.Pointer
+	// isNull reports whether the pointer constant is the null literal, as
+	// opposed to a reference to a named global.
+	isNull bool
+	// name holds the identifier of the referenced global (e.g. "foo" for
+	// "@foo"); only meaningful when isNull is false.
+	name string
+}
+
+// NewNullPointer returns a pointer constant representing the null literal of
+// the given pointer type.
+func NewNullPointer(typ *types.Pointer) *Pointer {
+	return &Pointer{typ: typ, isNull: true}
 }
 
-// TODO: Figure out how to represent pointer constants. Add the necessary fields
-// to the Pointer struct and implement the NewPointer constructor afterwards.
+// NewGlobalPointer returns a pointer constant referencing the named global
+// (e.g. a global variable or function) of the given pointer type.
+//
+// TODO: Once symbol tables exist, validate that the pointee type of typ
+// matches the type of the referenced global.
+func NewGlobalPointer(typ *types.Pointer, name string) *Pointer {
+	return &Pointer{typ: typ, name: name}
+}
 
 // Type returns the type of the value.
 func (v *Pointer) Type() types.Type {
 	return v.typ
 }
 
+// Name returns the identifier of the global referenced by the pointer
+// constant, or "" if it represents the null literal.
+func (v *Pointer) Name() string {
+	return v.name
+}
+
+// String returns a string representation of the pointer constant, e.g.
+//
+//    i32* null
+//    i32* @foo
+func (v *Pointer) String() string {
+	if v.isNull {
+		return fmt.Sprintf("%s null", v.Type())
+	}
+	return fmt.Sprintf("%s @%s", v.Type(), v.name)
+}
+
 // isConst ensures that only constant values can be assigned to the Constant
 // interface.
 func (*Int) isConst()     {}
@@ -0,0 +1,190 @@
+package consts
+
+import (
+	"math"
+	"testing"
+
+	"github.com/llir/llvm/types"
+)
+
+func TestNewIntBigWidth(t *testing.T) {
+	tests := []struct {
+		typ  *types.Int
+		s    string
+		want string
+	}{
+		// i128 round-trips values above math.MaxInt64.
+		{typ: types.NewInt(128), s: "340282366920938463463374607431768211455", want: "340282366920938463463374607431768211455"},
+		{typ: types.NewInt(128), s: "170141183460469231731687303715884105727", want: "170141183460469231731687303715884105727"},
+		// Negative values near -2^127.
+		{typ: types.NewInt(128), s: "-170141183460469231731687303715884105728", want: "-170141183460469231731687303715884105728"},
+		{typ: types.NewInt(128), s: "-170141183460469231731687303715884105727", want: "-170141183460469231731687303715884105727"},
+		// i256.
+		{typ: types.NewInt(256), s: "115792089237316195423570985008687907853269984665640564039457584007913129639935", want: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+	}
+	for _, test := range tests {
+		v, err := NewInt(test.typ, test.s)
+		if err != nil {
+			t.Errorf("NewInt(%v, %q) returned error %v", test.typ, test.s, err)
+			continue
+		}
+		got := v.x.String()
+		if got != test.want {
+			t.Errorf("NewInt(%v, %q).x = %s, want %s", test.typ, test.s, got, test.want)
+		}
+	}
+}
+
+func TestNewIntOverflow(t *testing.T) {
+	// One past the maximum (unsigned) i128 value, 2^128.
+	_, err := NewInt(types.NewInt(128), "340282366920938463463374607431768211456")
+	if err == nil {
+		t.Fatalf("expected overflow error for i128 value one past max, got none")
+	}
+	// One past the minimum negative i128 value.
+	_, err = NewInt(types.NewInt(128), "-170141183460469231731687303715884105729")
+	if err == nil {
+		t.Fatalf("expected overflow error for i128 value one past min, got none")
+	}
+}
+
+func TestIntStringBig(t *testing.T) {
+	v, err := NewInt(types.NewInt(128), "-170141183460469231731687303715884105728")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	want := "i128 -170141183460469231731687303715884105728"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestNewIntHex(t *testing.T) {
+	tests := []struct {
+		typ  *types.Int
+		s    string
+		want string
+	}{
+		// s0xFF in i8 == -1.
+		{typ: types.NewInt(8), s: "s0xFF", want: "-1"},
+		// u0xFF in i8 == 255.
+		{typ: types.NewInt(8), s: "u0xFF", want: "255"},
+		// s0x7F in i8 == 127.
+		{typ: types.NewInt(8), s: "s0x7F", want: "127"},
+		// Long hex strings for i128.
+		{typ: types.NewInt(128), s: "u0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", want: "340282366920938463463374607431768211455"},
+		{typ: types.NewInt(128), s: "s0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", want: "-1"},
+	}
+	for _, test := range tests {
+		v, err := NewInt(test.typ, test.s)
+		if err != nil {
+			t.Errorf("NewInt(%v, %q) returned error %v", test.typ, test.s, err)
+			continue
+		}
+		if got := v.x.String(); got != test.want {
+			t.Errorf("NewInt(%v, %q).x = %s, want %s", test.typ, test.s, got, test.want)
+		}
+	}
+}
+
+func TestNewFloatSignalingNaN(t *testing.T) {
+	// A signaling NaN double: exponent all ones, mantissa MSB (the
+	// quiet/signaling bit) clear, some other mantissa bit set.
+	const hex = "0x7FF4000000000000"
+	v, err := NewFloat(types.Float64, hex)
+	if err != nil {
+		t.Fatalf("NewFloat(double, %q) returned error %v", hex, err)
+	}
+	bits := math.Float64bits(v.x)
+	if bits != 0x7FF4000000000000 {
+		t.Errorf("NewFloat(double, %q).x has bits 0x%016X, want 0x7FF4000000000000", hex, bits)
+	}
+	if got := v.String(); got != "double "+hex {
+		t.Errorf("String() = %q, want %q", got, "double "+hex)
+	}
+}
+
+func TestNewFloatSignalingNaNFloat32(t *testing.T) {
+	// The same signaling NaN, narrowed to float32: low 29 bits are zero, so
+	// it is exactly representable, and the signaling bit must survive the
+	// narrow-then-widen round trip intact.
+	const hex = "0x7FF4000000000000"
+	v, err := NewFloat(types.Float32, hex)
+	if err != nil {
+		t.Fatalf("NewFloat(float, %q) returned error %v", hex, err)
+	}
+	bits := math.Float64bits(v.x)
+	if bits != 0x7FF4000000000000 {
+		t.Errorf("NewFloat(float, %q).x has bits 0x%016X, want 0x7FF4000000000000 (signaling bit must not be quieted)", hex, bits)
+	}
+}
+
+func TestNewFloatSubnormal(t *testing.T) {
+	// The double bit pattern exactly equal to 2^-149, the smallest positive
+	// float32 subnormal; it must narrow rather than be rejected.
+	const hex = "0x36A0000000000000"
+	v, err := NewFloat(types.Float32, hex)
+	if err != nil {
+		t.Fatalf("NewFloat(float, %q) returned error %v", hex, err)
+	}
+	if got, want := v.x, math.Float64frombits(0x36A0000000000000); got != want {
+		t.Errorf("NewFloat(float, %q).x = %v, want %v", hex, got, want)
+	}
+}
+
+func TestFloatNegativeZero(t *testing.T) {
+	v, err := NewFloat(types.Float64, "0x8000000000000000")
+	if err != nil {
+		t.Fatalf("NewFloat returned error %v", err)
+	}
+	want := "double 0x8000000000000000"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFloatInf(t *testing.T) {
+	v, err := NewFloat(types.Float64, "0x7FF0000000000000")
+	if err != nil {
+		t.Fatalf("NewFloat returned error %v", err)
+	}
+	want := "double 0x7FF0000000000000"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestNullPointerString(t *testing.T) {
+	typ := types.NewPointer(types.NewInt(32))
+	v := NewNullPointer(typ)
+	want := "i32* null"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := v.Name(); got != "" {
+		t.Errorf("Name() = %q, want empty", got)
+	}
+}
+
+func TestGlobalPointerString(t *testing.T) {
+	typ := types.NewPointer(types.NewInt(32))
+	v := NewGlobalPointer(typ, "foo")
+	want := "i32* @foo"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := v.Name(); got != "foo" {
+		t.Errorf("Name() = %q, want %q", got, "foo")
+	}
+}
+
+func TestIntHexString(t *testing.T) {
+	v, err := NewInt(types.NewInt(8), "-1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	want := "s0xFF"
+	if got := v.HexString(); got != want {
+		t.Errorf("HexString() = %q, want %q", got, want)
+	}
+}
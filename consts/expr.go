@@ -0,0 +1,354 @@
+package consts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/types"
+)
+
+// ConstGEP represents a constant getelementptr expression.
+//
+// Examples:
+//    getelementptr inbounds ([10 x i32], [10 x i32]* @arr, i32 0, i32 3)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#getelementptr-expression
+type ConstGEP struct {
+	Src      Constant
+	Indices  []Constant
+	InBounds bool
+
+	typ types.Type
+}
+
+// NewConstGEP returns a constant getelementptr expression based on the given
+// source pointer constant and index constants.
+func NewConstGEP(src Constant, indices []Constant, inBounds bool) (*ConstGEP, error) {
+	srcType, ok := src.Type().(*types.Pointer)
+	if !ok {
+		return nil, fmt.Errorf("invalid source type for getelementptr expression; expected pointer, got %q", src.Type())
+	}
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("missing indices for getelementptr expression")
+	}
+
+	// Every index, including the first (which walks through the source
+	// pointer itself), must be an integer constant.
+	for _, index := range indices {
+		if _, ok := index.(*Int); !ok {
+			return nil, fmt.Errorf("invalid index into getelementptr expression; expected integer constant, got %T", index)
+		}
+	}
+
+	// The first index walks through the source pointer itself; the
+	// remaining indices walk through the pointee type.
+	elem := types.Type(srcType.Elem())
+	for _, index := range indices[1:] {
+		idx := int(index.(*Int).x.Int64())
+		switch t := elem.(type) {
+		case *types.Array:
+			if idx < 0 || idx >= t.Len() {
+				return nil, fmt.Errorf("invalid array index %d; type %q has %d elements", idx, t, t.Len())
+			}
+			elem = t.Elem()
+		case *types.Struct:
+			fields := t.Fields()
+			if idx < 0 || idx >= len(fields) {
+				return nil, fmt.Errorf("invalid struct index %d; type %q has %d fields", idx, t, len(fields))
+			}
+			elem = fields[idx]
+		default:
+			return nil, fmt.Errorf("unable to index into type %q", elem)
+		}
+	}
+
+	v := &ConstGEP{
+		Src:      src,
+		Indices:  indices,
+		InBounds: inBounds,
+		typ:      types.NewPointer(elem),
+	}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *ConstGEP) Type() types.Type {
+	return v.typ
+}
+
+// String returns a string representation of the getelementptr expression,
+// e.g.
+//
+//    getelementptr inbounds ([10 x i32], [10 x i32]* @arr, i32 0, i32 3)
+func (v *ConstGEP) String() string {
+	inBounds := ""
+	if v.InBounds {
+		inBounds = "inbounds "
+	}
+	srcType := v.Src.Type().(*types.Pointer).Elem()
+	args := make([]string, 0, len(v.Indices)+1)
+	args = append(args, fmt.Sprintf("%s, %s", srcType, v.Src))
+	for _, index := range v.Indices {
+		args = append(args, index.String())
+	}
+	return fmt.Sprintf("getelementptr %s(%s)", inBounds, strings.Join(args, ", "))
+}
+
+// ConstBitCast represents a constant bitcast expression.
+//
+// Examples:
+//    bitcast (i8* @foo to i32*)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#bitcast-to-instruction
+type ConstBitCast struct {
+	From Constant
+	To   types.Type
+}
+
+// NewConstBitCast returns a constant bitcast expression which converts the
+// given constant to the given type.
+func NewConstBitCast(from Constant, to types.Type) *ConstBitCast {
+	return &ConstBitCast{From: from, To: to}
+}
+
+// Type returns the type of the value.
+func (v *ConstBitCast) Type() types.Type {
+	return v.To
+}
+
+// String returns a string representation of the bitcast expression, e.g.
+//
+//    bitcast (i8* @foo to i32*)
+func (v *ConstBitCast) String() string {
+	return fmt.Sprintf("bitcast (%s to %s)", v.From, v.To)
+}
+
+// ConstIntToPtr represents a constant inttoptr expression.
+//
+// Examples:
+//    inttoptr (i64 4294967296 to i8*)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#inttoptr-to-instruction
+type ConstIntToPtr struct {
+	From Constant
+	To   *types.Pointer
+}
+
+// NewConstIntToPtr returns a constant inttoptr expression which converts the
+// given integer constant to the given pointer type.
+func NewConstIntToPtr(from Constant, to *types.Pointer) (*ConstIntToPtr, error) {
+	if _, ok := from.Type().(*types.Int); !ok {
+		return nil, fmt.Errorf("invalid source type for inttoptr expression; expected integer, got %q", from.Type())
+	}
+	v := &ConstIntToPtr{From: from, To: to}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *ConstIntToPtr) Type() types.Type {
+	return v.To
+}
+
+// String returns a string representation of the inttoptr expression, e.g.
+//
+//    inttoptr (i64 4294967296 to i8*)
+func (v *ConstIntToPtr) String() string {
+	return fmt.Sprintf("inttoptr (%s to %s)", v.From, v.To)
+}
+
+// ConstPtrToInt represents a constant ptrtoint expression.
+//
+// Examples:
+//    ptrtoint (i8* @foo to i64)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#ptrtoint-to-instruction
+type ConstPtrToInt struct {
+	From Constant
+	To   *types.Int
+}
+
+// NewConstPtrToInt returns a constant ptrtoint expression which converts the
+// given pointer constant to the given integer type.
+func NewConstPtrToInt(from Constant, to *types.Int) (*ConstPtrToInt, error) {
+	if _, ok := from.Type().(*types.Pointer); !ok {
+		return nil, fmt.Errorf("invalid source type for ptrtoint expression; expected pointer, got %q", from.Type())
+	}
+	v := &ConstPtrToInt{From: from, To: to}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *ConstPtrToInt) Type() types.Type {
+	return v.To
+}
+
+// String returns a string representation of the ptrtoint expression, e.g.
+//
+//    ptrtoint (i8* @foo to i64)
+func (v *ConstPtrToInt) String() string {
+	return fmt.Sprintf("ptrtoint (%s to %s)", v.From, v.To)
+}
+
+// constIntConv represents a constant integer conversion expression shared by
+// trunc, zext and sext.
+type constIntConv struct {
+	op   string
+	From Constant
+	To   *types.Int
+}
+
+// newConstIntConv returns a constant integer conversion expression, verifying
+// that From is an integer constant.
+func newConstIntConv(op string, from Constant, to *types.Int) (*constIntConv, error) {
+	if _, ok := from.Type().(*types.Int); !ok {
+		return nil, fmt.Errorf("invalid source type for %s expression; expected integer, got %q", op, from.Type())
+	}
+	v := &constIntConv{op: op, From: from, To: to}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *constIntConv) Type() types.Type {
+	return v.To
+}
+
+// String returns a string representation of the integer conversion
+// expression, e.g.
+//
+//    trunc (i32 255 to i8)
+func (v *constIntConv) String() string {
+	return fmt.Sprintf("%s (%s to %s)", v.op, v.From, v.To)
+}
+
+// isConst ensures that only constant values can be assigned to the Constant
+// interface.
+func (*constIntConv) isConst() {}
+
+// ConstTrunc represents a constant trunc expression.
+//
+// Examples:
+//    trunc (i32 255 to i8)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#trunc-to-instruction
+type ConstTrunc struct {
+	*constIntConv
+}
+
+// NewConstTrunc returns a constant trunc expression which truncates the
+// given integer constant to the given (narrower) integer type.
+func NewConstTrunc(from Constant, to *types.Int) (*ConstTrunc, error) {
+	c, err := newConstIntConv("trunc", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ConstTrunc{c}, nil
+}
+
+// ConstZExt represents a constant zext expression.
+//
+// Examples:
+//    zext (i8 255 to i32)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#zext-to-instruction
+type ConstZExt struct {
+	*constIntConv
+}
+
+// NewConstZExt returns a constant zext expression which zero-extends the
+// given integer constant to the given (wider) integer type.
+func NewConstZExt(from Constant, to *types.Int) (*ConstZExt, error) {
+	c, err := newConstIntConv("zext", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ConstZExt{c}, nil
+}
+
+// ConstSExt represents a constant sext expression.
+//
+// Examples:
+//    sext (i8 -1 to i32)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#sext-to-instruction
+type ConstSExt struct {
+	*constIntConv
+}
+
+// NewConstSExt returns a constant sext expression which sign-extends the
+// given integer constant to the given (wider) integer type.
+func NewConstSExt(from Constant, to *types.Int) (*ConstSExt, error) {
+	c, err := newConstIntConv("sext", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ConstSExt{c}, nil
+}
+
+// ConstBinaryExpr represents a constant binary expression.
+//
+// Examples:
+//    add (i32 1, i32 2)
+//    xor (i32 1, i32 3)
+//
+// References:
+//    http://llvm.org/docs/LangRef.html#constant-expressions
+type ConstBinaryExpr struct {
+	// Op is the binary operator, one of "add", "sub", "mul", "and", "or" or
+	// "xor".
+	Op   string
+	X, Y Constant
+}
+
+// constBinaryOps are the binary operators supported by constant binary
+// expressions.
+var constBinaryOps = map[string]bool{
+	"add": true,
+	"sub": true,
+	"mul": true,
+	"and": true,
+	"or":  true,
+	"xor": true,
+}
+
+// NewConstBinaryExpr returns a constant binary expression based on the given
+// operator and operand constants.
+func NewConstBinaryExpr(op string, x, y Constant) (*ConstBinaryExpr, error) {
+	if !constBinaryOps[op] {
+		return nil, fmt.Errorf("invalid constant binary expression operator %q", op)
+	}
+	if _, ok := x.Type().(*types.Int); !ok {
+		return nil, fmt.Errorf("invalid operand type for %s expression; expected integer, got %q", op, x.Type())
+	}
+	if x.Type().String() != y.Type().String() {
+		return nil, fmt.Errorf("type mismatch between %s operands; %q and %q", op, x.Type(), y.Type())
+	}
+	v := &ConstBinaryExpr{Op: op, X: x, Y: y}
+	return v, nil
+}
+
+// Type returns the type of the value.
+func (v *ConstBinaryExpr) Type() types.Type {
+	return v.X.Type()
+}
+
+// String returns a string representation of the binary expression, e.g.
+//
+//    add (i32 1, i32 2)
+func (v *ConstBinaryExpr) String() string {
+	return fmt.Sprintf("%s (%s, %s)", v.Op, v.X, v.Y)
+}
+
+// isConst ensures that only constant values can be assigned to the Constant
+// interface.
+func (*ConstGEP) isConst()        {}
+func (*ConstBitCast) isConst()    {}
+func (*ConstIntToPtr) isConst()   {}
+func (*ConstPtrToInt) isConst()   {}
+func (*ConstBinaryExpr) isConst() {}
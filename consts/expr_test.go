@@ -0,0 +1,191 @@
+package consts
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/types"
+)
+
+func TestConstGEPString(t *testing.T) {
+	i32 := types.NewInt(32)
+	arrType := types.NewArray(i32, 10)
+	ptrType := types.NewPointer(arrType)
+	src := NewGlobalPointer(ptrType, "arr")
+	idx0, err := NewInt(i32, "0")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	idx1, err := NewInt(i32, "3")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v, err := NewConstGEP(src, []Constant{idx0, idx1}, true)
+	if err != nil {
+		t.Fatalf("NewConstGEP returned error %v", err)
+	}
+	want := "getelementptr inbounds ([10 x i32], [10 x i32]* @arr, i32 0, i32 3)"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestConstGEPInvalidArrayIndex(t *testing.T) {
+	i32 := types.NewInt(32)
+	arrType := types.NewArray(i32, 10)
+	ptrType := types.NewPointer(arrType)
+	src := NewGlobalPointer(ptrType, "arr")
+	idx0, err := NewInt(i32, "0")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	// Out of bounds array index.
+	idx1, err := NewInt(i32, "10")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	if _, err := NewConstGEP(src, []Constant{idx0, idx1}, false); err == nil {
+		t.Fatalf("expected error for out-of-bounds array index, got none")
+	}
+}
+
+func TestConstGEPNonIntegerIndex(t *testing.T) {
+	i32 := types.NewInt(32)
+	arrType := types.NewArray(i32, 10)
+	ptrType := types.NewPointer(arrType)
+	src := NewGlobalPointer(ptrType, "arr")
+	idx0, err := NewInt(i32, "0")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	// A pointer constant is not a valid index.
+	badIdx := NewNullPointer(types.NewPointer(i32))
+	if _, err := NewConstGEP(src, []Constant{idx0, badIdx}, false); err == nil {
+		t.Fatalf("expected error for non-integer index, got none")
+	}
+}
+
+func TestConstBitCastString(t *testing.T) {
+	i8ptr := types.NewPointer(types.NewInt(8))
+	i32ptr := types.NewPointer(types.NewInt(32))
+	src := NewGlobalPointer(i8ptr, "foo")
+	v := NewConstBitCast(src, i32ptr)
+	want := "bitcast (i8* @foo to i32*)"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestConstIntToPtrString(t *testing.T) {
+	i64 := types.NewInt(64)
+	i8ptr := types.NewPointer(types.NewInt(8))
+	from, err := NewInt(i64, "4294967296")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v, err := NewConstIntToPtr(from, i8ptr)
+	if err != nil {
+		t.Fatalf("NewConstIntToPtr returned error %v", err)
+	}
+	want := "inttoptr (i64 4294967296 to i8*)"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestConstPtrToIntString(t *testing.T) {
+	i64 := types.NewInt(64)
+	i8ptr := types.NewPointer(types.NewInt(8))
+	from := NewGlobalPointer(i8ptr, "foo")
+	v, err := NewConstPtrToInt(from, i64)
+	if err != nil {
+		t.Fatalf("NewConstPtrToInt returned error %v", err)
+	}
+	want := "ptrtoint (i8* @foo to i64)"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestConstIntConvString(t *testing.T) {
+	i8, i32 := types.NewInt(8), types.NewInt(32)
+
+	trunc, err := NewInt(i32, "255")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v1, err := NewConstTrunc(trunc, i8)
+	if err != nil {
+		t.Fatalf("NewConstTrunc returned error %v", err)
+	}
+	if want, got := "trunc (i32 255 to i8)", v1.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	zext, err := NewInt(i8, "255")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v2, err := NewConstZExt(zext, i32)
+	if err != nil {
+		t.Fatalf("NewConstZExt returned error %v", err)
+	}
+	if want, got := "zext (i8 255 to i32)", v2.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	sext, err := NewInt(i8, "-1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	v3, err := NewConstSExt(sext, i32)
+	if err != nil {
+		t.Fatalf("NewConstSExt returned error %v", err)
+	}
+	if want, got := "sext (i8 -1 to i32)", v3.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestConstBinaryExprString(t *testing.T) {
+	i32 := types.NewInt(32)
+	tests := []struct {
+		op   string
+		x, y string
+		want string
+	}{
+		{op: "add", x: "1", y: "2", want: "add (i32 1, i32 2)"},
+		{op: "xor", x: "1", y: "3", want: "xor (i32 1, i32 3)"},
+	}
+	for _, test := range tests {
+		x, err := NewInt(i32, test.x)
+		if err != nil {
+			t.Fatalf("NewInt returned error %v", err)
+		}
+		y, err := NewInt(i32, test.y)
+		if err != nil {
+			t.Fatalf("NewInt returned error %v", err)
+		}
+		v, err := NewConstBinaryExpr(test.op, x, y)
+		if err != nil {
+			t.Fatalf("NewConstBinaryExpr returned error %v", err)
+		}
+		if got := v.String(); got != test.want {
+			t.Errorf("String() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestConstBinaryExprInvalidOp(t *testing.T) {
+	i32 := types.NewInt(32)
+	x, err := NewInt(i32, "1")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	y, err := NewInt(i32, "2")
+	if err != nil {
+		t.Fatalf("NewInt returned error %v", err)
+	}
+	if _, err := NewConstBinaryExpr("udiv", x, y); err == nil {
+		t.Fatalf("expected error for unsupported operator, got none")
+	}
+}
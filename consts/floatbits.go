@@ -0,0 +1,88 @@
+package consts
+
+// narrowFloat64BitsToFloat32Bits converts the raw bit pattern of a float64
+// into the raw bit pattern of the float32 it exactly represents, reporting
+// ok == false if the value is not exactly representable (any of the low 29
+// mantissa bits are set, or the value lies outside the float32 range,
+// subnormals included).
+//
+// This operates on the bit pattern directly, rather than through a Go
+// language float64-to-float32 conversion, so that NaN payloads and the
+// quiet/signaling bit are preserved exactly; a hardware narrowing
+// conversion is free to quiet a signaling NaN, which would corrupt the
+// value LLVM's assembly asked for.
+func narrowFloat64BitsToFloat32Bits(bits uint64) (bits32 uint32, ok bool) {
+	if bits&0x1FFFFFFF != 0 {
+		return 0, false
+	}
+	sign := uint32(bits>>63) & 1
+	exp := int64((bits>>52)&0x7FF) - 1023
+	mant := uint32((bits >> 29) & 0x7FFFFF)
+
+	switch {
+	case exp == 1024:
+		// Infinity or NaN; the exponent field is all ones and the mantissa
+		// (including the quiet/signaling bit) carries over unchanged.
+		return sign<<31 | 0xFF<<23 | mant, true
+	case exp == -1023 && mant == 0:
+		// Zero.
+		return sign << 31, true
+	case exp < -126:
+		// Below the float32 normal range; renormalize into a float32
+		// subnormal (0.fraction * 2^-126) if the value is exactly
+		// representable there, mirroring widenFloat32BitsToFloat64Bits's
+		// subnormal case in reverse.
+		shift := uint(-126 - exp)
+		full := uint32(0x800000) | mant // restore the implicit leading bit.
+		if shift >= 24 || full&(1<<shift-1) != 0 {
+			// Too small, or the shifted-out bits are non-zero: not exactly
+			// representable as a float32 (not even as a subnormal).
+			return 0, false
+		}
+		return sign<<31 | full>>shift, true
+	case exp > 127:
+		// Outside the float32 range entirely.
+		return 0, false
+	default:
+		return sign<<31 | uint32(exp+127)<<23 | mant, true
+	}
+}
+
+// widenFloat32BitsToFloat64Bits converts the raw bit pattern of a float32
+// into the raw bit pattern of the float64 that exactly represents it.
+//
+// This is done on the bit pattern directly rather than through a Go
+// language float32-to-float64 conversion: that widening is mathematically
+// exact, but is not guaranteed to be quiet-bit-preserving in practice — a
+// signaling NaN can come back from a float64(float32Value) conversion with
+// its signaling bit flipped to quiet, which would corrupt the value LLVM's
+// assembly asked for.
+func widenFloat32BitsToFloat64Bits(bits32 uint32) uint64 {
+	sign := uint64(bits32>>31) & 1
+	exp := uint64(bits32>>23) & 0xFF
+	mant := uint64(bits32) & 0x7FFFFF
+
+	switch exp {
+	case 0:
+		if mant == 0 {
+			// Zero.
+			return sign << 63
+		}
+		// Subnormal float32; renormalize into float64, which has ample
+		// range to represent it as a normal value.
+		e := int64(-126)
+		for mant&0x800000 == 0 {
+			mant <<= 1
+			e--
+		}
+		mant &= 0x7FFFFF
+		return sign<<63 | uint64(e+1023)<<52 | mant<<29
+	case 0xFF:
+		// Infinity or NaN; the exponent field is all ones and the mantissa
+		// (including the quiet/signaling bit) carries over, zero-extended.
+		return sign<<63 | uint64(0x7FF)<<52 | mant<<29
+	default:
+		e := int64(exp) - 127
+		return sign<<63 | uint64(e+1023)<<52 | mant<<29
+	}
+}
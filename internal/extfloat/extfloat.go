@@ -0,0 +1,243 @@
+// Package extfloat implements encoding and decoding between arbitrary
+// precision floating point values (math/big.Float) and the raw bit patterns
+// of the extended floating point formats used by LLVM IR: binary16 (half),
+// the x87 80-bit extended format (x86_fp80), binary128 (fp128), and the
+// PowerPC double-double format (ppc_fp128).
+//
+// math/big.Float itself only represents a value and a precision; it has no
+// notion of these LLVM-specific bit layouts, so this package bridges the
+// two: Encode* turns a *big.Float into the raw bits LLVM expects in its
+// hexadecimal constant syntax, and Decode* recovers a *big.Float from those
+// bits.
+package extfloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// ieeeFormat describes the layout of a normal (implicit leading mantissa
+// bit) IEEE-754 binary floating point format, as used by half and fp128.
+type ieeeFormat struct {
+	expBits  uint
+	mantBits uint
+	bias     int64
+}
+
+var (
+	halfFormat  = ieeeFormat{expBits: 5, mantBits: 10, bias: 15}
+	fp128Format = ieeeFormat{expBits: 15, mantBits: 112, bias: 16383}
+)
+
+// EncodeHalf encodes f as the 16-bit raw bit pattern of an IEEE-754 binary16
+// (half precision) value.
+func EncodeHalf(f *big.Float) uint16 {
+	return uint16(encodeIEEE(f, halfFormat).Uint64())
+}
+
+// DecodeHalf decodes the 16-bit raw bit pattern of an IEEE-754 binary16
+// value into a *big.Float.
+func DecodeHalf(bits uint16) *big.Float {
+	return decodeIEEE(new(big.Int).SetUint64(uint64(bits)), halfFormat)
+}
+
+// EncodeFP128 encodes f as the 128-bit raw bit pattern of an IEEE-754
+// binary128 (quad precision) value.
+func EncodeFP128(f *big.Float) *big.Int {
+	return encodeIEEE(f, fp128Format)
+}
+
+// DecodeFP128 decodes the 128-bit raw bit pattern of an IEEE-754 binary128
+// value into a *big.Float.
+func DecodeFP128(bits *big.Int) *big.Float {
+	return decodeIEEE(bits, fp128Format)
+}
+
+// encodeIEEE encodes f into the raw bits of a normal IEEE-754 format with an
+// implicit leading mantissa bit.
+func encodeIEEE(f *big.Float, format ieeeFormat) *big.Int {
+	width := 1 + format.expBits + format.mantBits
+	bits := new(big.Int)
+
+	sign := f.Signbit()
+	af := new(big.Float).SetPrec(format.mantBits + 64).Abs(f)
+
+	if af.Sign() == 0 {
+		if sign {
+			bits.SetBit(bits, int(width-1), 1)
+		}
+		return bits
+	}
+
+	// af = mant * 2^exp2, with mant in [0.5, 1).
+	mant := new(big.Float).SetPrec(format.mantBits + 64)
+	exp2 := af.MantExp(mant)
+	// Normalize to 1.xxx * 2^e.
+	e := int64(exp2) - 1
+	sig := new(big.Float).SetPrec(format.mantBits + 64).Mul(mant, big.NewFloat(2))
+
+	// Round the significand (1.xxx) to format.mantBits fraction bits.
+	// sig*scale lies in [2^mantBits, 2^(mantBits+1)), so rounding the product
+	// to format.mantBits+1 bits of precision (big.Float's default rounding
+	// mode is round-to-nearest-even) is exactly rounding it to the nearest
+	// integer; the subsequent Int then extracts that integer exactly, and
+	// removing the implicit leading bit leaves the fractional mantissa.
+	scale := new(big.Float).SetPrec(format.mantBits + 64).SetInt(new(big.Int).Lsh(big.NewInt(1), format.mantBits))
+	scaled := new(big.Float).SetPrec(format.mantBits+1).Mul(sig, scale)
+	mantInt, _ := scaled.Int(nil)
+	mantInt.Sub(mantInt, new(big.Int).Lsh(big.NewInt(1), format.mantBits))
+
+	// Rounding can carry the fractional mantissa up to exactly 2^mantBits
+	// (i.e. 1.0), which doubles the value; carry that into the exponent
+	// instead of letting the extra bit spill into the exponent field.
+	if mantInt.BitLen() > int(format.mantBits) {
+		e++
+		mantInt = new(big.Int)
+	}
+
+	expField := e + format.bias
+	if sign {
+		bits.SetBit(bits, int(width-1), 1)
+	}
+	bits.Or(bits, new(big.Int).Lsh(big.NewInt(expField), format.mantBits))
+	bits.Or(bits, mantInt)
+	return bits
+}
+
+// decodeIEEE decodes the raw bits of a normal IEEE-754 format with an
+// implicit leading mantissa bit into a *big.Float.
+func decodeIEEE(bits *big.Int, format ieeeFormat) *big.Float {
+	width := 1 + format.expBits + format.mantBits
+	mantMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), format.mantBits), big.NewInt(1))
+	mant := new(big.Int).And(bits, mantMask)
+	expField := new(big.Int).Rsh(bits, format.mantBits)
+	expMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), format.expBits), big.NewInt(1))
+	expField.And(expField, expMask)
+	sign := bits.Bit(int(width-1)) == 1
+
+	result := new(big.Float).SetPrec(format.mantBits + 64)
+	if expField.Sign() == 0 && mant.Sign() == 0 {
+		if sign {
+			result.Neg(result)
+		}
+		return result
+	}
+
+	e := expField.Int64() - format.bias
+	result.SetInt(mant)
+	result.Quo(result, new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), format.mantBits)))
+	result.Add(result, big.NewFloat(1))
+	// result is (1+frac) in [1, 2); encodeIEEE defines af == (1+frac)·2^e, so
+	// no further +1 belongs in the exponent here.
+	result.SetMantExp(result, int(e))
+	if sign {
+		result.Neg(result)
+	}
+	return result
+}
+
+// EncodeX87Ext encodes f as the 80-bit raw bit pattern of an x86 extended
+// precision (x86_fp80) value: a 1-bit sign, a 15-bit biased exponent, and a
+// 64-bit mantissa with an explicit (non-implicit) leading integer bit.
+func EncodeX87Ext(f *big.Float) *big.Int {
+	const expBits, mantBits, bias = 15, 64, 16383
+	bits := new(big.Int)
+
+	sign := f.Signbit()
+	af := new(big.Float).SetPrec(mantBits + 64).Abs(f)
+	if af.Sign() == 0 {
+		if sign {
+			bits.SetBit(bits, expBits+mantBits, 1)
+		}
+		return bits
+	}
+
+	mant := new(big.Float).SetPrec(mantBits + 64)
+	exp2 := af.MantExp(mant)
+	e := int64(exp2) - 1
+	// Explicit leading bit: scale mant (in [0.5,1)) by 2^mantBits to get the
+	// full 64-bit mantissa field, including its leading 1 bit.
+	scale := new(big.Float).SetPrec(mantBits + 64).SetInt(new(big.Int).Lsh(big.NewInt(1), mantBits))
+	full := new(big.Float).SetPrec(mantBits + 64).Mul(mant, big.NewFloat(2))
+	full.Mul(full, new(big.Float).Quo(scale, big.NewFloat(2)))
+	// Round the final mantissa to mantBits bits of precision (rather than
+	// truncating), matching the round-to-nearest-even behavior used for the
+	// implicit-leading-bit formats above.
+	rounded := new(big.Float).SetPrec(mantBits).Set(full)
+	mantInt, _ := rounded.Int(nil)
+
+	// As in encodeIEEE, rounding can carry the mantissa up to exactly
+	// 2^mantBits; renormalize by bumping the exponent and resetting the
+	// mantissa to 1.0 (explicit leading bit set, fraction zero).
+	if mantInt.BitLen() > mantBits {
+		e++
+		mantInt = new(big.Int).Lsh(big.NewInt(1), mantBits-1)
+	}
+
+	expField := e + bias
+	if sign {
+		bits.SetBit(bits, expBits+mantBits, 1)
+	}
+	bits.Or(bits, new(big.Int).Lsh(big.NewInt(expField), mantBits))
+	bits.Or(bits, mantInt)
+	return bits
+}
+
+// DecodeX87Ext decodes the 80-bit raw bit pattern of an x86_fp80 value into
+// a *big.Float.
+func DecodeX87Ext(bits *big.Int) *big.Float {
+	const expBits, mantBits, bias = 15, 64, 16383
+	mantMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), mantBits), big.NewInt(1))
+	mant := new(big.Int).And(bits, mantMask)
+	expField := new(big.Int).Rsh(bits, mantBits)
+	expMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), expBits), big.NewInt(1))
+	expField.And(expField, expMask)
+	sign := bits.Bit(expBits+mantBits) == 1
+
+	result := new(big.Float).SetPrec(mantBits + 64)
+	if expField.Sign() == 0 && mant.Sign() == 0 {
+		if sign {
+			result.Neg(result)
+		}
+		return result
+	}
+
+	e := expField.Int64() - bias
+	result.SetInt(mant)
+	result.Quo(result, new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), mantBits)))
+	// mant already includes the explicit leading bit, i.e. result is in
+	// [0.5, 1); the value is result * 2^(e+1).
+	result.SetMantExp(result, int(e)+1)
+	if sign {
+		result.Neg(result)
+	}
+	return result
+}
+
+// EncodePPCDouble encodes f as the 128-bit raw bit pattern of a PowerPC
+// double-double (ppc_fp128) value: a pair of IEEE-754 binary64 values (hi,
+// lo), where hi is f rounded to binary64 and lo is the remainder f - hi
+// rounded to binary64, packed as hi's bits followed by lo's bits.
+func EncodePPCDouble(f *big.Float) *big.Int {
+	hi, _ := f.Float64()
+	rem := new(big.Float).SetPrec(f.Prec() + 64).Sub(f, big.NewFloat(hi))
+	lo, _ := rem.Float64()
+
+	bits := new(big.Int).SetUint64(math.Float64bits(hi))
+	bits.Lsh(bits, 64)
+	bits.Or(bits, new(big.Int).SetUint64(math.Float64bits(lo)))
+	return bits
+}
+
+// DecodePPCDouble decodes the 128-bit raw bit pattern of a ppc_fp128 value
+// into a *big.Float.
+func DecodePPCDouble(bits *big.Int) *big.Float {
+	mask64 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1))
+	lo := new(big.Int).And(bits, mask64).Uint64()
+	hi := new(big.Int).And(new(big.Int).Rsh(bits, 64), mask64).Uint64()
+
+	result := new(big.Float).SetPrec(128)
+	result.SetFloat64(math.Float64frombits(hi))
+	result.Add(result, new(big.Float).SetFloat64(math.Float64frombits(lo)))
+	return result
+}
@@ -0,0 +1,101 @@
+package extfloat
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestHalfRoundTrip(t *testing.T) {
+	tests := []struct {
+		s    string
+		bits uint16
+	}{
+		{s: "0", bits: 0x0000},
+		{s: "1", bits: 0x3C00},
+		{s: "1.5", bits: 0x3E00},
+		{s: "-2", bits: 0xC000},
+		{s: "0.5", bits: 0x3800},
+	}
+	for _, test := range tests {
+		f, ok := new(big.Float).SetPrec(64).SetString(test.s)
+		if !ok {
+			t.Fatalf("unable to parse %q", test.s)
+		}
+		bits := EncodeHalf(f)
+		if bits != test.bits {
+			t.Errorf("EncodeHalf(%s) = 0x%04X, want 0x%04X", test.s, bits, test.bits)
+		}
+		got := DecodeHalf(bits)
+		want, _ := new(big.Float).SetPrec(64).SetString(test.s)
+		if got.Cmp(want) != 0 {
+			t.Errorf("DecodeHalf(EncodeHalf(%s)) = %s, want %s", test.s, got.Text('g', -1), want.Text('g', -1))
+		}
+	}
+}
+
+func TestHalfMantissaOverflow(t *testing.T) {
+	// 1.999755859375 rounds to half precision as exactly 2.0: the fractional
+	// mantissa rounds up to 2^mantBits, which must carry into the exponent
+	// rather than corrupt it.
+	f, ok := new(big.Float).SetPrec(64).SetString("1.999755859375")
+	if !ok {
+		t.Fatalf("unable to parse %q", "1.999755859375")
+	}
+	const want = 0x4000 // 2.0
+	bits := EncodeHalf(f)
+	if bits != want {
+		t.Errorf("EncodeHalf(1.999755859375) = 0x%04X, want 0x%04X", bits, want)
+	}
+	got := DecodeHalf(bits)
+	if got.Cmp(big.NewFloat(2)) != 0 {
+		t.Errorf("DecodeHalf(EncodeHalf(1.999755859375)) = %s, want 2", got.Text('g', -1))
+	}
+}
+
+func TestFP128RoundTrip(t *testing.T) {
+	tests := []string{"0", "1", "1.5", "-2", "0.5", "0.25"}
+	for _, s := range tests {
+		f, ok := new(big.Float).SetPrec(128).SetString(s)
+		if !ok {
+			t.Fatalf("unable to parse %q", s)
+		}
+		bits := EncodeFP128(f)
+		got := DecodeFP128(bits)
+		want, _ := new(big.Float).SetPrec(128).SetString(s)
+		if got.Cmp(want) != 0 {
+			t.Errorf("DecodeFP128(EncodeFP128(%s)) = %s, want %s", s, got.Text('g', -1), want.Text('g', -1))
+		}
+	}
+}
+
+func TestX87ExtRoundTrip(t *testing.T) {
+	tests := []string{"0", "1", "1.5", "-2", "0.5", "0.25"}
+	for _, s := range tests {
+		f, ok := new(big.Float).SetPrec(128).SetString(s)
+		if !ok {
+			t.Fatalf("unable to parse %q", s)
+		}
+		bits := EncodeX87Ext(f)
+		got := DecodeX87Ext(bits)
+		want, _ := new(big.Float).SetPrec(128).SetString(s)
+		if got.Cmp(want) != 0 {
+			t.Errorf("DecodeX87Ext(EncodeX87Ext(%s)) = %s, want %s", s, got.Text('g', -1), want.Text('g', -1))
+		}
+	}
+}
+
+func TestPPCDoubleRoundTrip(t *testing.T) {
+	tests := []string{"0", "1", "1.5", "-2", "0.5", "0.25"}
+	for _, s := range tests {
+		f, ok := new(big.Float).SetPrec(128).SetString(s)
+		if !ok {
+			t.Fatalf("unable to parse %q", s)
+		}
+		bits := EncodePPCDouble(f)
+		got := DecodePPCDouble(bits)
+		want, _ := new(big.Float).SetPrec(128).SetString(s)
+		if got.Cmp(want) != 0 {
+			t.Errorf("DecodePPCDouble(EncodePPCDouble(%s)) = %s, want %s", s, got.Text('g', -1), want.Text('g', -1))
+		}
+	}
+}